@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// -------------- 本机指标采集 --------------
+//
+// 面向 Linux 部署的最小实现：CPU/内存读 /proc，磁盘用 statfs。没有接第三方
+// 采集库，够 `tems agent` 这种轻量场景用。
+
+func collectLocalMetric() (Metric, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return Metric{}, fmt.Errorf("agent: hostname: %w", err)
+	}
+
+	cpuPct, err := readCPUPercent()
+	if err != nil {
+		return Metric{}, fmt.Errorf("agent: cpu: %w", err)
+	}
+	memPct, err := readMemPercent()
+	if err != nil {
+		return Metric{}, fmt.Errorf("agent: mem: %w", err)
+	}
+	diskPct, err := readDiskPercent("/")
+	if err != nil {
+		return Metric{}, fmt.Errorf("agent: disk: %w", err)
+	}
+
+	return Metric{
+		Hostname: hostname,
+		IP:       firstNonLoopbackIPv4(),
+		CPU:      cpuPct,
+		Mem:      memPct,
+		Disk:     diskPct,
+	}, nil
+}
+
+type cpuSample struct {
+	idle  uint64
+	total uint64
+}
+
+func readCPUSample() (cpuSample, error) {
+	b, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return cpuSample{}, err
+	}
+	firstLine := strings.SplitN(string(b), "\n", 2)[0]
+	fields := strings.Fields(firstLine) // cpu user nice system idle iowait irq softirq steal
+	if len(fields) < 5 {
+		return cpuSample{}, fmt.Errorf("unexpected /proc/stat format: %q", firstLine)
+	}
+
+	var sample cpuSample
+	for i, f := range fields[1:] {
+		v, err := strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			continue
+		}
+		sample.total += v
+		if i == 3 { // idle 字段
+			sample.idle = v
+		}
+	}
+	return sample, nil
+}
+
+// readCPUPercent 采两次 /proc/stat 样本算区间内的 CPU 占用率。
+func readCPUPercent() (float64, error) {
+	before, err := readCPUSample()
+	if err != nil {
+		return 0, err
+	}
+	time.Sleep(200 * time.Millisecond)
+	after, err := readCPUSample()
+	if err != nil {
+		return 0, err
+	}
+
+	totalDelta := float64(after.total - before.total)
+	idleDelta := float64(after.idle - before.idle)
+	if totalDelta <= 0 {
+		return 0, nil
+	}
+	return (1 - idleDelta/totalDelta) * 100, nil
+}
+
+func readMemPercent() (float64, error) {
+	b, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+
+	var total, available float64
+	for _, line := range strings.Split(string(b), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total, _ = strconv.ParseFloat(fields[1], 64)
+		case "MemAvailable:":
+			available, _ = strconv.ParseFloat(fields[1], 64)
+		}
+	}
+	if total == 0 {
+		return 0, fmt.Errorf("MemTotal not found in /proc/meminfo")
+	}
+	return (1 - available/total) * 100, nil
+}
+
+func readDiskPercent(path string) (float64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	total := stat.Blocks * uint64(stat.Bsize)
+	if total == 0 {
+		return 0, nil
+	}
+	free := stat.Bfree * uint64(stat.Bsize)
+	return float64(total-free) / float64(total) * 100, nil
+}
+
+func firstNonLoopbackIPv4() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return ""
+	}
+	for _, a := range addrs {
+		ipNet, ok := a.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() {
+			continue
+		}
+		if ip4 := ipNet.IP.To4(); ip4 != nil {
+			return ip4.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,231 @@
+// Package alerting 根据采集到的 Agent 指标做规则匹配，并把持续触发的
+// 情况分发给通知渠道。包本身不依赖 main 包的类型，避免循环引用。
+package alerting
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Severity 是告警的严重级别。
+type Severity string
+
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Metric 是 alerting 关心的指标字段子集。
+type Metric struct {
+	Hostname string
+	IP       string
+	CPU      float64
+	Mem      float64
+	Disk     float64
+	LastSeen int64
+}
+
+// RuleConfig 是 config.yaml 里 alerting.rules 下的一条规则。Threshold 的
+// 单位取决于 Type：cpu/mem/disk 是百分比，last_seen 是秒。
+type RuleConfig struct {
+	Type      string        `yaml:"type"`
+	Threshold float64       `yaml:"threshold"`
+	For       time.Duration `yaml:"for"`
+	Severity  string        `yaml:"severity"`
+}
+
+// Rule 判断单条指标是否命中某种异常条件。
+type Rule interface {
+	Match(m Metric) bool
+	Type() string
+	UpdateFromConfig(cfg RuleConfig) error
+}
+
+// Alert 是一次规则触发（或恢复）的记录，Resolved=true 表示这是一条
+// "恢复通知"。
+type Alert struct {
+	RuleType string   `json:"rule_type"`
+	Hostname string   `json:"hostname"`
+	Severity Severity `json:"severity"`
+	Since    int64    `json:"since"`
+	Resolved bool     `json:"resolved"`
+}
+
+// Notifier 把一条 Alert 发送到具体渠道（webhook、邮件、IM 机器人等）。
+type Notifier interface {
+	Notify(a Alert) error
+}
+
+type thresholdRule struct {
+	field     string // cpu, mem, disk
+	threshold float64
+}
+
+func (r *thresholdRule) Type() string { return r.field }
+
+func (r *thresholdRule) Match(m Metric) bool {
+	var v float64
+	switch r.field {
+	case "cpu":
+		v = m.CPU
+	case "mem":
+		v = m.Mem
+	case "disk":
+		v = m.Disk
+	}
+	return v > r.threshold
+}
+
+func (r *thresholdRule) UpdateFromConfig(cfg RuleConfig) error {
+	r.threshold = cfg.Threshold
+	return nil
+}
+
+type lastSeenRule struct {
+	maxAge time.Duration
+}
+
+func (r *lastSeenRule) Type() string { return "last_seen" }
+
+func (r *lastSeenRule) Match(m Metric) bool {
+	return time.Since(time.Unix(m.LastSeen, 0)) > r.maxAge
+}
+
+func (r *lastSeenRule) UpdateFromConfig(cfg RuleConfig) error {
+	r.maxAge = time.Duration(cfg.Threshold) * time.Second
+	return nil
+}
+
+func newRule(ruleType string) (Rule, error) {
+	switch ruleType {
+	case "cpu", "mem", "disk":
+		return &thresholdRule{field: ruleType}, nil
+	case "last_seen":
+		return &lastSeenRule{}, nil
+	default:
+		return nil, fmt.Errorf("alerting: unknown rule type %q", ruleType)
+	}
+}
+
+// ruleEntry 把一条 Rule 和 Router 自己要用的记账信息（条件要持续多久、
+// 触发后报多严重）打包在一起，这些信息 Rule 本身不关心。
+type ruleEntry struct {
+	rule     Rule
+	forDur   time.Duration
+	severity Severity
+}
+
+// ruleState 按"规则+hostname"记录条件从什么时候开始持续为真、
+// 以及是否已经触发过。
+type ruleState struct {
+	firstTrue time.Time
+	firing    bool
+}
+
+// Router 拿每条进来的 Metric 去跑所有配置的规则，只有条件持续为真超过
+// 它配置的 for 时长才真正触发，条件解除后再补发一条恢复通知。
+type Router struct {
+	mu        sync.Mutex
+	entries   []ruleEntry
+	states    map[string]*ruleState
+	firing    map[string]Alert
+	notifiers []Notifier
+}
+
+// NewRouter 构造一个把告警分发给指定 notifiers 的 Router。
+func NewRouter(notifiers ...Notifier) *Router {
+	return &Router{
+		states:    make(map[string]*ruleState),
+		firing:    make(map[string]Alert),
+		notifiers: notifiers,
+	}
+}
+
+// LoadConfig 根据 config.yaml 重建规则集，配置热加载时可以再次调用；
+// 规则类型还存在的话，对应的触发状态会按稳定的 state key 保留下来。
+func (r *Router) LoadConfig(cfgs []RuleConfig) error {
+	entries := make([]ruleEntry, 0, len(cfgs))
+	for _, c := range cfgs {
+		rule, err := newRule(c.Type)
+		if err != nil {
+			return err
+		}
+		if err := rule.UpdateFromConfig(c); err != nil {
+			return err
+		}
+		severity := Severity(c.Severity)
+		if severity == "" {
+			severity = SeverityWarning
+		}
+		entries = append(entries, ruleEntry{rule: rule, forDur: c.For, severity: severity})
+	}
+	r.mu.Lock()
+	r.entries = entries
+	r.mu.Unlock()
+	return nil
+}
+
+func stateKey(ruleType, hostname string) string {
+	return ruleType + "|" + hostname
+}
+
+// Evaluate 拿 m 去跑所有规则，按需触发或恢复告警。
+func (r *Router) Evaluate(m Metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, e := range r.entries {
+		key := stateKey(e.rule.Type(), m.Hostname)
+		st := r.states[key]
+		if st == nil {
+			st = &ruleState{}
+			r.states[key] = st
+		}
+
+		if !e.rule.Match(m) {
+			if st.firing {
+				st.firing = false
+				a := r.firing[key]
+				a.Resolved = true
+				delete(r.firing, key)
+				r.dispatch(a)
+			}
+			st.firstTrue = time.Time{}
+			continue
+		}
+
+		if st.firstTrue.IsZero() {
+			st.firstTrue = now
+		}
+		if !st.firing && now.Sub(st.firstTrue) >= e.forDur {
+			st.firing = true
+			a := Alert{
+				RuleType: e.rule.Type(),
+				Hostname: m.Hostname,
+				Severity: e.severity,
+				Since:    st.firstTrue.Unix(),
+			}
+			r.firing[key] = a
+			r.dispatch(a)
+		}
+	}
+}
+
+func (r *Router) dispatch(a Alert) {
+	for _, n := range r.notifiers {
+		go n.Notify(a) //nolint:errcheck // 尽力而为的通知，失败了也没有更好的处理方式
+	}
+}
+
+// FiringAlerts 返回当前所有正在触发的告警快照，给 /api/alerts 用。
+func (r *Router) FiringAlerts() []Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Alert, 0, len(r.firing))
+	for _, a := range r.firing {
+		out = append(out, a)
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+)
+
+// chanNotifier 把告警发到 channel 上，而不是直接写共享 slice——dispatch
+// 是用 go n.Notify(a) 异步调的，测试这边必须用能安全跨 goroutine
+// 同步的东西来收，不能直接读写一个裸 slice。
+type chanNotifier chan Alert
+
+func (n chanNotifier) Notify(a Alert) error {
+	n <- a
+	return nil
+}
+
+func recvAlert(t *testing.T, ch chanNotifier) Alert {
+	t.Helper()
+	select {
+	case a := <-ch:
+		return a
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for notification")
+		return Alert{}
+	}
+}
+
+func assertNoAlert(t *testing.T, ch chanNotifier) {
+	t.Helper()
+	select {
+	case a := <-ch:
+		t.Fatalf("unexpected notification: %+v", a)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRouterForDurationAndResolve(t *testing.T) {
+	notifier := make(chanNotifier, 8)
+	r := NewRouter(notifier)
+	if err := r.LoadConfig([]RuleConfig{
+		{Type: "cpu", Threshold: 90, For: 2 * time.Second, Severity: "critical"},
+	}); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	m := Metric{Hostname: "h1", CPU: 95}
+
+	// 条件刚满足，还没撑满 For 时长，不应该触发。
+	r.Evaluate(m)
+	if got := len(r.FiringAlerts()); got != 0 {
+		t.Fatalf("firing alerts before For elapses = %d, want 0", got)
+	}
+	assertNoAlert(t, notifier)
+
+	// 手动把状态往前拨，模拟条件已经持续超过 For。
+	r.mu.Lock()
+	r.states[stateKey("cpu", "h1")].firstTrue = time.Now().Add(-3 * time.Second)
+	r.mu.Unlock()
+	r.Evaluate(m)
+
+	fired := recvAlert(t, notifier)
+	if fired.Severity != SeverityCritical || fired.Resolved {
+		t.Fatalf("unexpected alert: %+v", fired)
+	}
+	if firing := r.FiringAlerts(); len(firing) != 1 {
+		t.Fatalf("firing alerts after For elapses = %d, want 1", len(firing))
+	}
+
+	// 条件持续满足不应该重复触发。
+	r.Evaluate(m)
+	assertNoAlert(t, notifier)
+
+	// 条件解除后应该补发一条 Resolved=true 的通知，FiringAlerts 里也要清空。
+	r.Evaluate(Metric{Hostname: "h1", CPU: 10})
+	resolved := recvAlert(t, notifier)
+	if !resolved.Resolved {
+		t.Fatalf("expected a resolve notification, got %+v", resolved)
+	}
+	if got := len(r.FiringAlerts()); got != 0 {
+		t.Fatalf("firing alerts after clearing = %d, want 0", got)
+	}
+}
@@ -0,0 +1,121 @@
+package alerting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"time"
+)
+
+// WebhookNotifier POSTs the Alert as JSON to a generic webhook URL.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (w *WebhookNotifier) Notify(a Alert) error {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	resp, err := w.client().Post(w.URL, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alerting: webhook %s returned %s", w.URL, resp.Status)
+	}
+	return nil
+}
+
+func (w *WebhookNotifier) client() *http.Client {
+	if w.Client != nil {
+		return w.Client
+	}
+	return http.DefaultClient
+}
+
+// ChatWebhookNotifier posts a plain-text message to Slack/DingTalk-style
+// incoming webhooks, which both accept {"text": "..."}.
+type ChatWebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (c *ChatWebhookNotifier) Notify(a Alert) error {
+	status := "FIRING"
+	if a.Resolved {
+		status = "RESOLVED"
+	}
+	text := fmt.Sprintf("[%s] %s on %s (severity=%s)", status, a.RuleType, a.Hostname, a.Severity)
+	b, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Post(c.URL, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alerting: chat webhook %s returned %s", c.URL, resp.Status)
+	}
+	return nil
+}
+
+// SMTPNotifier emails the alert using net/smtp.
+type SMTPNotifier struct {
+	Addr string
+	From string
+	To   []string
+	Auth smtp.Auth
+}
+
+func (s *SMTPNotifier) Notify(a Alert) error {
+	subject := fmt.Sprintf("[TEMS] %s alert on %s", a.RuleType, a.Hostname)
+	if a.Resolved {
+		subject = fmt.Sprintf("[TEMS] %s alert on %s resolved", a.RuleType, a.Hostname)
+	}
+	body := fmt.Sprintf("rule=%s hostname=%s severity=%s since=%s resolved=%v",
+		a.RuleType, a.Hostname, a.Severity, time.Unix(a.Since, 0).Format(time.RFC3339), a.Resolved)
+	msg := fmt.Sprintf("Subject: %s\r\n\r\n%s\r\n", subject, body)
+	return smtp.SendMail(s.Addr, s.Auth, s.From, s.To, []byte(msg))
+}
+
+// TEPSForwardNotifier forwards the alert to TEPS with a severity tag,
+// reusing the same JSON-over-HTTP transport as the regular metric push.
+type TEPSForwardNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (t *TEPSForwardNotifier) Notify(a Alert) error {
+	payload := map[string]interface{}{
+		"type":  "alert",
+		"alert": a,
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Post(t.URL, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alerting: teps forward returned %s", resp.Status)
+	}
+	return nil
+}
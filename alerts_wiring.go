@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/smtp"
+	"time"
+
+	"github.com/GmanStak/go-ibm-tems/alerting"
+)
+
+// -------------- 告警路由的装配 --------------
+//
+// alerting 包本身不知道 config.yaml 长什么样，这里负责把
+// cfg.Alerting 翻译成 alerting.RuleConfig / alerting.Notifier。
+
+var alertRouter *alerting.Router
+
+func buildNotifiers(c Config) []alerting.Notifier {
+	var notifiers []alerting.Notifier
+
+	for _, url := range c.Alerting.Notifiers.Webhook {
+		notifiers = append(notifiers, &alerting.WebhookNotifier{URL: url})
+	}
+	for _, url := range c.Alerting.Notifiers.Slack {
+		notifiers = append(notifiers, &alerting.ChatWebhookNotifier{URL: url})
+	}
+	if c.Alerting.Notifiers.SMTP.Addr != "" {
+		smtpCfg := c.Alerting.Notifiers.SMTP
+		var auth smtp.Auth
+		if smtpCfg.User != "" {
+			auth = smtp.PlainAuth("", smtpCfg.User, smtpCfg.Pass, smtpHost(smtpCfg.Addr))
+		}
+		notifiers = append(notifiers, &alerting.SMTPNotifier{
+			Addr: smtpCfg.Addr,
+			From: smtpCfg.From,
+			To:   smtpCfg.To,
+			Auth: auth,
+		})
+	}
+	if c.Alerting.Notifiers.ForwardTEPS {
+		notifiers = append(notifiers, &alerting.TEPSForwardNotifier{URL: c.TEPSURL})
+	}
+	return notifiers
+}
+
+// smtpHost 去掉 addr 里的端口，net/smtp.PlainAuth 要的是裸 host。
+func smtpHost(addr string) string {
+	for i := len(addr) - 1; i >= 0; i-- {
+		if addr[i] == ':' {
+			return addr[:i]
+		}
+	}
+	return addr
+}
+
+func toAlertingMetric(m Metric) alerting.Metric {
+	return alerting.Metric{
+		Hostname: m.Hostname,
+		IP:       m.IP,
+		CPU:      m.CPU,
+		Mem:      m.Mem,
+		Disk:     m.Disk,
+		LastSeen: m.LastSeen,
+	}
+}
+
+// runAlertSweep 周期性地把 Store 里的每个 agent 重新跑一遍规则评估，直到
+// ctx 被取消。metricsHandler/ingestGRPC 只在 agent 刚推送数据时才会调用
+// Evaluate，像 last_seen 这种"agent 停止上报"的规则必须靠这个独立的
+// 轮询才能命中——否则一个掉线的 agent 永远不会再触发新的 Evaluate 调用。
+func runAlertSweep(ctx context.Context, c Config) {
+	interval := c.Alerting.SweepInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sweepOnce()
+		}
+	}
+}
+
+func sweepOnce() {
+	list, err := store.List()
+	if err != nil {
+		log.Printf("alerting: sweep: list store: %v", err)
+		return
+	}
+	for _, m := range list {
+		alertRouter.Evaluate(toAlertingMetric(m))
+	}
+}
+
+// alertsHandler 暴露当前所有正在触发的告警。
+func alertsHandler(w http.ResponseWriter, r *http.Request) {
+	_ = json.NewEncoder(w).Encode(alertRouter.FiringAlerts())
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newAgentCmd 是给没有专门 Agent 进程的主机用的最小采集器：定期读本机
+// CPU/内存/磁盘占用，JSON POST 到某个 TEMS 的 /metrics。
+func newAgentCmd() *cobra.Command {
+	var target string
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Run a lightweight collector that posts local metrics to a TEMS /metrics endpoint",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := loadConfig(cfgPath)
+			if target == "" {
+				target = c.Agent.TargetURL
+			}
+			if target == "" {
+				return fmt.Errorf("agent: no target URL (set agent.target_url in config.yaml or pass --target)")
+			}
+			runAgent(c, target)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&target, "target", "", "TEMS /metrics URL to push to (overrides agent.target_url)")
+	return cmd
+}
+
+func runAgent(c Config, target string) {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	for {
+		m, err := collectLocalMetric()
+		if err != nil {
+			log.Printf("agent: collect: %v", err)
+		} else if err := pushMetric(target, m); err != nil {
+			log.Printf("agent: push to %s: %v", target, err)
+		}
+		time.Sleep(interval)
+	}
+}
+
+func pushMetric(target string, m Metric) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(target, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/GmanStak/go-ibm-tems/alerting"
+	"github.com/gorilla/mux"
+	"github.com/spf13/cobra"
+)
+
+// newAPICmd 是原来唯一的 main()：起存储、告警路由、TEPS 发布者和 HTTP(S)
+// 服务，对外提供 Agent 推送、dashboard 和查询 API。
+func newAPICmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "api",
+		Short: "Run the TEMS HTTP(S) server: agent ingestion, dashboard and query API",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runAPI()
+			return nil
+		},
+	}
+}
+
+func runAPI() {
+	cfg = loadConfig(cfgPath)
+	s, err := newStore(cfg)
+	if err != nil {
+		log.Fatalf("init store: %v", err)
+	}
+	store = s
+
+	alertRouter = alerting.NewRouter(buildNotifiers(cfg)...)
+	if err := alertRouter.LoadConfig(cfg.Alerting.Rules); err != nil {
+		log.Fatalf("alerting config: %v", err)
+	}
+
+	initIngestLimiter(cfg)
+
+	pushCtx, cancelPush := context.WithCancel(context.Background())
+	publisher := newTEPSPublisher(cfg)
+	go publisher.Run(pushCtx)
+	go runGRPCServer(pushCtx, cfg)
+	go runAlertSweep(pushCtx, cfg)
+	if cfg.Cron.Embedded {
+		go runCronEmbedded(pushCtx, cfg)
+	}
+
+	r := mux.NewRouter()
+
+	// 0) 公共中间件链：访问日志、request-id、CORS、panic 恢复、gzip，
+	// public 和 protected 路由共用
+	applyBaseChain(r, cfg)
+
+	// 1) 公开端点：Agent 推送。开了 tls.client_ca_file 就要求客户端证书，
+	// 用 mTLS 替代 Basic Auth；这个要求只加在 /metrics 上，不影响同一个
+	// HTTPS 监听口上的其它路由。
+	metricsChain := http.Handler(http.HandlerFunc(metricsHandler))
+	if cfg.TLS.ClientCAFile != "" {
+		metricsChain = requireClientCert(cfg.TLS.AllowedCNs, metricsChain)
+	}
+	r.Handle("/metrics", withTimeout(metricsChain, cfg)).Methods("POST")
+
+	// 根路径重定向
+	r.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/web/", http.StatusFound)
+	})
+
+	// 2) 需要 Basic Auth 的子路由
+	protected := r.PathPrefix("/").Subrouter()
+	protected.Handle("/api", withTimeout(http.HandlerFunc(apiHandler), cfg))
+	protected.Handle("/api/alerts", withTimeout(http.HandlerFunc(alertsHandler), cfg))
+	protected.Handle("/api/agents/{hostname}/command", withTimeout(http.HandlerFunc(agentCommandHandler), cfg)).Methods("POST")
+	protected.HandleFunc("/ws", wsHandler) // WebSocket 升级，不能套超时/gzip 包装
+	protected.PathPrefix("/web/").Handler(webHandler())
+
+	if cfg.Prometheus.Enabled {
+		path := cfg.Prometheus.Path
+		if path == "" {
+			path = "/metrics/prom"
+		}
+		protected.Handle(path, withTimeout(promHandler(), cfg))
+	}
+
+	// 3) 只对 protected 子路由加鉴权
+	protected.Use(func(next http.Handler) http.Handler {
+		return basicAuth(cfg.Basic.User, cfg.Basic.Pass, next)
+	})
+
+	log.Printf("dashboard : http://localhost:8080")
+	log.Printf("TEMS %s ready | /metrics (Agent) | /web (Dashboard) -> TEPS %s",
+		cfg.TEMSName, cfg.TEPSURL)
+	runServers(r, cfg)
+	cancelPush()
+}
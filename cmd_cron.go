@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newCronCmd 跑周期性的汇总/清理任务：目前是对 Store 做一次保留期清理，
+// 把超过 cron.retention 没上报过的 agent 从存储里删掉。
+//
+// 注意：这是独立进程，自己开一个 Store。storage.backend=bolt 时 BoltDB
+// 的文件锁是进程级独占的，跟同时跑着的 `tems api` 抢同一个库文件会直接
+// 拿不到锁失败——这种部署形态应该把 cron.embedded 打开，让 `tems api`
+// 自己内嵌跑这个循环，而不是再单独起 `tems cron` 进程。
+func newCronCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cron",
+		Short: "Run scheduled rollup/retention jobs against the store",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := loadConfig(cfgPath)
+			s, err := newStore(c)
+			if err != nil {
+				return err
+			}
+			store = s
+			runCron(c)
+			return nil
+		},
+	}
+}
+
+func runCron(c Config) {
+	interval := c.Cron.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	rollupOnce(c)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		rollupOnce(c)
+	}
+}
+
+// runCronEmbedded 是 cron.embedded 模式下跑的循环：逻辑跟 runCron 一样，
+// 只是用 `tems api` 自己已经开好的 Store，且随 ctx 一起退出，不用单独起
+// 一个 `tems cron` 进程去抢同一个 BoltDB 文件的锁。
+func runCronEmbedded(ctx context.Context, c Config) {
+	interval := c.Cron.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	rollupOnce(c)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rollupOnce(c)
+		}
+	}
+}
+
+func rollupOnce(c Config) {
+	list, err := store.List()
+	if err != nil {
+		log.Printf("cron: list store: %v", err)
+		return
+	}
+
+	retention := c.Cron.Retention
+	if retention <= 0 {
+		retention = 24 * time.Hour
+	}
+	cutoff := time.Now().Add(-retention).Unix()
+
+	var active, expired int
+	for _, m := range list {
+		if m.LastSeen >= cutoff {
+			active++
+			continue
+		}
+		if err := store.Delete(m.Hostname); err != nil {
+			log.Printf("cron: delete %s: %v", m.Hostname, err)
+			continue
+		}
+		expired++
+	}
+	log.Printf("cron: rollup complete: %d active, %d expired", active, expired)
+}
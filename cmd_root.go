@@ -0,0 +1,32 @@
+package main
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+// -------------- 子命令入口 --------------
+//
+// tems 不再是单一用途的二进制：api 跑现有的 HTTP 服务，agent 是个轻量
+// 采集器，cron 跑周期性的汇总/清理任务，migrate 初始化存储 schema。
+// 四个子命令共用同一个 Config 加载器，但只启动各自需要的 goroutine。
+
+var cfgPath string
+
+func main() {
+	root := &cobra.Command{
+		Use:   "tems",
+		Short: "TEMS — Tivoli Enterprise Management Server relay",
+	}
+	root.PersistentFlags().StringVar(&cfgPath, "config", "config.yaml", "path to config.yaml")
+
+	root.AddCommand(newAPICmd())
+	root.AddCommand(newAgentCmd())
+	root.AddCommand(newCronCmd())
+	root.AddCommand(newMigrateCmd())
+
+	if err := root.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/GmanStak/go-ibm-tems/pb"
+)
+
+// -------------- gRPC ingestion --------------
+//
+// tepsGRPCServer 是 /metrics 的第二条腿：面向高频 agent 的流式上报，跟 HTTP
+// 共用同一个 Store、同一套 validateMetric/allowIngest，鉴权用 mTLS（配置了
+// grpc.tls.client_ca_file 时走证书）或者一个共享 bearer token。
+
+type tepsGRPCServer struct {
+	pb.UnimplementedTEMSServer
+}
+
+// fromPB 把 gRPC 上收到的 Metric 转成 main.Metric。Network/Processes 在
+// proto 里是预先 json.Marshal 过的 bytes（HTTP 这边本来就是未成形的
+// map/slice），这里再 Unmarshal 回同样的形状，保证 gRPC 和 HTTP 两条摄取
+// 路径产出同一套指标，不会让走 gRPC 的 agent 丢了 network/process 那几个
+// Prometheus 指标。
+func fromPB(m *pb.Metric) Metric {
+	out := Metric{
+		Hostname: m.GetHostname(),
+		IP:       m.GetIp(),
+		CPU:      m.GetCpuPercent(),
+		Mem:      m.GetMemPercent(),
+		Disk:     m.GetDiskPercent(),
+		LastSeen: m.GetLastSeen(),
+	}
+	if b := m.GetNetworkJson(); len(b) > 0 {
+		if err := json.Unmarshal(b, &out.Network); err != nil {
+			log.Printf("grpc: decode network_json for %s: %v", out.Hostname, err)
+		}
+	}
+	if b := m.GetProcessesJson(); len(b) > 0 {
+		if err := json.Unmarshal(b, &out.Processes); err != nil {
+			log.Printf("grpc: decode processes_json for %s: %v", out.Hostname, err)
+		}
+	}
+	return out
+}
+
+// authorizeGRPC 检查 grpc.token；没配置 token 的时候认为鉴权已经在传输层由
+// mTLS 做掉了（跟 server.go 里 /metrics 用 mTLS 替代 Basic Auth 是一个思路）。
+func authorizeGRPC(ctx context.Context) error {
+	if cfg.GRPC.Token == "" {
+		return nil
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing metadata")
+	}
+	for _, v := range md.Get("authorization") {
+		if strings.TrimPrefix(v, "Bearer ") == cfg.GRPC.Token {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "invalid or missing token")
+}
+
+// ingestGRPC 把一条 Metric 落到 Store 并推给订阅者/告警路由，跟 metricsHandler
+// 走的是同一套校验、限流和下游逻辑，只是错误要包成 gRPC status。
+func ingestGRPC(m Metric) error {
+	if err := validateMetric(m); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if !allowIngest() {
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	m.LastSeen = time.Now().Unix()
+	if err := store.Put(m); err != nil {
+		return status.Errorf(codes.Internal, "store: %v", err)
+	}
+	hub.publish(m)
+	alertRouter.Evaluate(toAlertingMetric(m))
+	return nil
+}
+
+// PushMetrics 是高频 agent 的单向流式上报：client 连续发 Metric，server 连续
+// 写 Store，流结束时返回收到的条数。
+func (s *tepsGRPCServer) PushMetrics(stream pb.TEMS_PushMetricsServer) error {
+	if err := authorizeGRPC(stream.Context()); err != nil {
+		return err
+	}
+	var received int64
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&pb.PushMetricsResponse{Received: received})
+		}
+		if err != nil {
+			return err
+		}
+		if err := ingestGRPC(fromPB(in)); err != nil {
+			return err
+		}
+		received++
+	}
+}
+
+// commandHub 是 Control 的下发通道：每个已连接的 agent 在 hub 里占一个以
+// hostname 为 key 的 channel，agentCommandHandler 把 /api/agents 收到的
+// 命令塞进对应 channel，Control 的发送循环再把它们 Send 给那条流。
+type commandHub struct {
+	mu    sync.Mutex
+	conns map[string]chan *pb.AgentCommand
+}
+
+func newCommandHub() *commandHub {
+	return &commandHub{conns: make(map[string]chan *pb.AgentCommand)}
+}
+
+func (h *commandHub) register(hostname string) chan *pb.AgentCommand {
+	ch := make(chan *pb.AgentCommand, 8)
+	h.mu.Lock()
+	h.conns[hostname] = ch
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *commandHub) unregister(hostname string, ch chan *pb.AgentCommand) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.conns[hostname] == ch {
+		delete(h.conns, hostname)
+		close(ch)
+	}
+}
+
+// send 把 cmd 塞进 hostname 对应的 Control 流队列；agent 当前没连上，
+// 或者队列积压满了，都返回 error。
+func (h *commandHub) send(hostname string, cmd *pb.AgentCommand) error {
+	h.mu.Lock()
+	ch := h.conns[hostname]
+	h.mu.Unlock()
+	if ch == nil {
+		return fmt.Errorf("agent %q is not connected on the Control stream", hostname)
+	}
+	select {
+	case ch <- cmd:
+		return nil
+	default:
+		return fmt.Errorf("agent %q command queue is full", hostname)
+	}
+}
+
+var commands = newCommandHub()
+
+// Control 是双向流：agent 在同一条流上报指标/ack，server 通过 commandHub
+// 随时塞一条 AgentCommand 下去（按需刷新、下发新配置），下发来源是
+// agentCommandHandler。recv 在单独的 goroutine 里跑，发送留在当前
+// goroutine，这是 gRPC 双向流推荐的读写分离方式。
+func (s *tepsGRPCServer) Control(stream pb.TEMS_ControlServer) error {
+	if err := authorizeGRPC(stream.Context()); err != nil {
+		return err
+	}
+
+	hostnameCh := make(chan string, 1)
+	recvErr := make(chan error, 1)
+	go func() {
+		var announced bool
+		for {
+			evt, err := stream.Recv()
+			if err == io.EOF {
+				recvErr <- nil
+				return
+			}
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			m := evt.GetMetric()
+			if m == nil {
+				continue
+			}
+			if !announced {
+				announced = true
+				hostnameCh <- m.GetHostname()
+			}
+			if err := ingestGRPC(fromPB(m)); err != nil {
+				recvErr <- err
+				return
+			}
+		}
+	}()
+
+	var hostname string
+	var queue chan *pb.AgentCommand
+	for {
+		select {
+		case err := <-recvErr:
+			if queue != nil {
+				commands.unregister(hostname, queue)
+			}
+			return err
+		case hostname = <-hostnameCh:
+			queue = commands.register(hostname)
+		case cmd := <-queue:
+			if err := stream.Send(cmd); err != nil {
+				commands.unregister(hostname, queue)
+				return err
+			}
+		}
+	}
+}
+
+// agentCommandRequest 是 POST /api/agents/{hostname}/command 的请求体。
+type agentCommandRequest struct {
+	Type       string `json:"type"`        // "refresh" 或 "config_push"
+	ConfigYAML string `json:"config_yaml"` // type == "config_push" 时才用
+}
+
+// agentCommandHandler 让运维能给一个当前连在 gRPC Control 流上的 agent
+// 推一次按需刷新或者下发新配置，是 commandHub.send 唯一的调用方。
+func agentCommandHandler(w http.ResponseWriter, r *http.Request) {
+	hostname := mux.Vars(r)["hostname"]
+
+	var req agentCommandRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var typ pb.AgentCommand_Type
+	switch req.Type {
+	case "refresh":
+		typ = pb.AgentCommand_REFRESH
+	case "config_push":
+		typ = pb.AgentCommand_CONFIG_PUSH
+	default:
+		http.Error(w, fmt.Sprintf("unknown command type %q", req.Type), http.StatusBadRequest)
+		return
+	}
+
+	cmd := &pb.AgentCommand{Type: typ, ConfigYaml: req.ConfigYAML}
+	if err := commands.send(hostname, cmd); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// runGRPCServer starts the gRPC listener on a second, independently
+// configurable port when grpc.enabled; it blocks until ctx is cancelled,
+// mirroring how runServers/waitForShutdown drain the HTTP listeners.
+func runGRPCServer(ctx context.Context, c Config) {
+	if !c.GRPC.Enabled {
+		return
+	}
+	addr := c.GRPC.ListenAddr
+	if addr == "" {
+		addr = ":9090"
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("grpc: listen: %v", err)
+	}
+
+	var opts []grpc.ServerOption
+	if c.GRPC.TLS.CertFile != "" {
+		tlsCfg, err := buildTLSConfig(c.GRPC.TLS)
+		if err != nil {
+			log.Fatalf("grpc: tls config: %v", err)
+		}
+		if c.GRPC.TLS.ClientCAFile != "" {
+			// gRPC 没有按路由挂的 requireClientCert，直接在握手层要求客户端证书。
+			tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		cert, err := tls.LoadX509KeyPair(c.GRPC.TLS.CertFile, c.GRPC.TLS.KeyFile)
+		if err != nil {
+			log.Fatalf("grpc: load cert: %v", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	}
+
+	srv := grpc.NewServer(opts...)
+	pb.RegisterTEMSServer(srv, &tepsGRPCServer{})
+
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+
+	log.Printf("grpc listening on %s", addr)
+	if err := srv.Serve(lis); err != nil {
+		log.Printf("grpc serve: %v", err)
+	}
+}
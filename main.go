@@ -4,17 +4,15 @@
 package main
 
 import (
-	"bytes"
 	"embed"
 	_ "embed"
 	"encoding/json"
 	"log"
 	"net/http"
 	"os"
-	"sync"
 	"time"
 
-	"github.com/gorilla/mux"
+	"github.com/GmanStak/go-ibm-tems/alerting"
 	"gopkg.in/yaml.v3"
 )
 
@@ -31,6 +29,74 @@ type Config struct {
 		User string `yaml:"user"`
 		Pass string `yaml:"pass"`
 	} `yaml:"basic"`
+	Prometheus struct {
+		Enabled    bool              `yaml:"enabled"`
+		Path       string            `yaml:"path"`
+		ProcessCPU bool              `yaml:"process_cpu"`
+		Relabel    map[string]string `yaml:"relabel"`
+	} `yaml:"prometheus"`
+	Storage struct {
+		Backend string `yaml:"backend"` // memory(默认)、bolt、sql
+		Bolt    struct {
+			Path string `yaml:"path"`
+		} `yaml:"bolt"`
+		SQL SQLConfig `yaml:"sql"`
+	} `yaml:"storage"`
+	Log struct {
+		AccessLog bool `yaml:"access_log"`
+	} `yaml:"log"`
+	Api struct {
+		RequestID bool          `yaml:"request_id"`
+		Recover   bool          `yaml:"recover"`
+		Gzip      bool          `yaml:"gzip"`
+		Timeout   time.Duration `yaml:"timeout"`
+		Cors      struct {
+			Enabled        bool     `yaml:"enabled"`
+			AllowedOrigins []string `yaml:"allowed_origins"`
+		} `yaml:"cors"`
+	} `yaml:"api"`
+	ListenAddrTLS string    `yaml:"listen_addr_tls"`
+	TLS           TLSConfig `yaml:"tls"`
+	H2C           bool      `yaml:"h2c"`
+	Alerting      struct {
+		Rules         []alerting.RuleConfig `yaml:"rules"`
+		SweepInterval time.Duration         `yaml:"sweep_interval"`
+		Notifiers     struct {
+			Webhook     []string `yaml:"webhook"`
+			Slack       []string `yaml:"slack"`
+			ForwardTEPS bool     `yaml:"forward_teps"`
+			SMTP        struct {
+				Addr string   `yaml:"addr"`
+				From string   `yaml:"from"`
+				To   []string `yaml:"to"`
+				User string   `yaml:"user"`
+				Pass string   `yaml:"pass"`
+			} `yaml:"smtp"`
+		} `yaml:"notifiers"`
+	} `yaml:"alerting"`
+	Teps  TEPSConfig `yaml:"teps"`
+	Agent struct {
+		TargetURL string `yaml:"target_url"`
+	} `yaml:"agent"`
+	Cron struct {
+		Interval  time.Duration `yaml:"interval"`
+		Retention time.Duration `yaml:"retention"`
+		// Embedded 为 true 时，`tems api` 自己跑 cron 的 rollup 循环，不用再
+		// 单独起一个 `tems cron` 进程。storage.backend=bolt 时必须这样用：
+		// BoltDB 的文件锁是进程级独占的，`tems api`+`tems cron` 各开一个
+		// Store 会导致后启动的那个直接拿不到锁。
+		Embedded bool `yaml:"embedded"`
+	} `yaml:"cron"`
+	GRPC struct {
+		Enabled    bool      `yaml:"enabled"`
+		ListenAddr string    `yaml:"listen_addr"`
+		Token      string    `yaml:"token"` // 不想上 mTLS 时，用一个共享 bearer token 做鉴权
+		TLS        TLSConfig `yaml:"tls"`
+		RateLimit  struct {
+			PerSecond float64 `yaml:"per_second"`
+			Burst     int     `yaml:"burst"`
+		} `yaml:"rate_limit"`
+	} `yaml:"grpc"`
 }
 
 // -------------- 数据结构 --------------
@@ -45,11 +111,10 @@ type Metric struct {
 	LastSeen  int64                  `json:"last_seen"`
 }
 
-// -------------- 内存存储 --------------
+// -------------- 存储 --------------
 var (
-	cfg      Config
-	metrics  = make(map[string]Metric) // key = hostname
-	metricsM sync.RWMutex
+	cfg   Config
+	store Store
 )
 
 func loadConfig(path string) Config {
@@ -85,75 +150,39 @@ func metricsHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "bad json", http.StatusBadRequest)
 		return
 	}
+	if err := validateMetric(m); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !allowIngest() {
+		http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+		return
+	}
 	m.LastSeen = time.Now().Unix()
-	metricsM.Lock()
-	metrics[m.Hostname] = m
-	metricsM.Unlock()
+	if err := store.Put(m); err != nil {
+		http.Error(w, "store", http.StatusInternalServerError)
+		return
+	}
+	hub.publish(m)
+	alertRouter.Evaluate(toAlertingMetric(m))
 	w.WriteHeader(http.StatusNoContent)
 }
 
 // -------------- 查询 API --------------
 func apiHandler(w http.ResponseWriter, r *http.Request) {
-	metricsM.RLock()
-	defer metricsM.RUnlock()
-	_ = json.NewEncoder(w).Encode(metrics)
-}
-
-// -------------- 推送给 TEPS --------------
-func pushToTEPS() {
-	ticker := time.NewTicker(cfg.Interval)
-	defer ticker.Stop()
-	for {
-		<-ticker.C
-		metricsM.RLock()
-		var list []Metric
-		for _, v := range metrics {
-			list = append(list, v)
-		}
-		metricsM.RUnlock()
-
-		payload := map[string]interface{}{
-			"tems_name": cfg.TEMSName,
-			"timestamp": time.Now().Unix(),
-			"agents":    list,
-		}
-		b, _ := json.Marshal(payload)
-		http.Post(cfg.TEPSURL, "application/json", bytes.NewReader(b))
+	list, err := store.List()
+	if err != nil {
+		http.Error(w, "store", http.StatusInternalServerError)
+		return
+	}
+	byHost := make(map[string]Metric, len(list))
+	for _, m := range list {
+		byHost[m.Hostname] = m
 	}
+	_ = json.NewEncoder(w).Encode(byHost)
 }
 
 // -------------- 启动 Web --------------
 func webHandler() http.Handler {
 	return http.StripPrefix("/", http.FileServer(http.FS(web)))
 }
-
-// -------------- main --------------
-func main() {
-	cfg = loadConfig("config.yaml")
-	go pushToTEPS()
-
-	r := mux.NewRouter()
-
-	// 1) 公开端点：Agent 推送
-	r.HandleFunc("/metrics", metricsHandler).Methods("POST")
-
-	// 根路径重定向
-	r.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		http.Redirect(w, r, "/web/", http.StatusFound)
-	})
-
-	// 2) 需要 Basic Auth 的子路由
-	protected := r.PathPrefix("/").Subrouter()
-	protected.HandleFunc("/api", apiHandler)
-	protected.PathPrefix("/web/").Handler(webHandler())
-
-	// 3) 只对 protected 子路由加中间件
-	protected.Use(func(next http.Handler) http.Handler {
-		return basicAuth(cfg.Basic.User, cfg.Basic.Pass, next)
-	})
-
-	log.Printf("dashboard : http://localhost:8080")
-	log.Printf("TEMS %s ready | /metrics (Agent) | /web (Dashboard) -> TEPS %s",
-		cfg.TEMSName, cfg.TEPSURL)
-	log.Fatal(http.ListenAndServe(cfg.ListenAddr, r))
-}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/cors"
+)
+
+// -------------- 中间件链 --------------
+//
+// 取代原先只包在 protected 子路由上的 basicAuth，这里把公共关注点拆成
+// 可独立开关的中间件，通过 config.yaml 的 [log]/[api] 段落控制，
+// public 的 /metrics 和 protected 的子路由共用同一条基础链，鉴权仍然只加在
+// protected 上。
+
+type ctxKey int
+
+const requestIDContextKey ctxKey = iota
+
+const requestIDHeader = "X-Request-Id"
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+// requestIDMiddleware 复用上游传入的 X-Request-Id（如果有），否则生成一个，
+// 写回响应头并塞进 context 供访问日志使用。
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		ctx := context.WithValue(r.Context(), requestIDContextKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// accessLogEntry 是一条结构化访问日志，方便直接喂给日志采集系统。
+type accessLogEntry struct {
+	Method    string  `json:"method"`
+	Path      string  `json:"path"`
+	Status    int     `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Remote    string  `json:"remote"`
+	RequestID string  `json:"request_id"`
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("access log: underlying ResponseWriter does not support Hijack")
+	}
+	return h.Hijack()
+}
+
+func accessLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		b, err := json.Marshal(accessLogEntry{
+			Method:    r.Method,
+			Path:      r.URL.Path,
+			Status:    rec.status,
+			LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			Remote:    r.RemoteAddr,
+			RequestID: requestIDFromContext(r.Context()),
+		})
+		if err != nil {
+			return
+		}
+		log.Println(string(b))
+	})
+}
+
+// recoverMiddleware 防止单个请求的 panic 打挂整个进程。
+func recoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("panic recovered: %v", rec)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz *gzip.Writer
+}
+
+func (w gzipResponseWriter) Write(b []byte) (int, error) {
+	return w.gz.Write(b)
+}
+
+func (w gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("gzip: underlying ResponseWriter does not support Hijack")
+	}
+	return h.Hijack()
+}
+
+// gzipMiddleware 只在客户端声明支持 gzip 时才压缩；WebSocket 升级走的是
+// Hijack，不经过 Write，所以对 /ws 是安全的。
+func gzipMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Add("Vary", "Accept-Encoding")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		next.ServeHTTP(gzipResponseWriter{ResponseWriter: w, gz: gz}, r)
+	})
+}
+
+// withTimeout 给单个路由加超时；Timeout <= 0 表示不限制。不要用来包
+// /ws，http.TimeoutHandler 不支持 Hijack，会打断 WebSocket 升级。
+func withTimeout(h http.Handler, c Config) http.Handler {
+	if c.Api.Timeout <= 0 {
+		return h
+	}
+	return http.TimeoutHandler(h, c.Api.Timeout, "request timeout")
+}
+
+// applyBaseChain 把各中间件按 config.yaml 的开关挂到路由上，/metrics 和
+// protected 子路由都经过同一个 r，所以两边共享这条链。
+func applyBaseChain(r *mux.Router, c Config) {
+	if c.Api.RequestID {
+		r.Use(requestIDMiddleware)
+	}
+	if c.Log.AccessLog {
+		r.Use(accessLogMiddleware)
+	}
+	if c.Api.Cors.Enabled {
+		co := cors.New(cors.Options{AllowedOrigins: c.Api.Cors.AllowedOrigins})
+		r.Use(co.Handler)
+	}
+	if c.Api.Recover {
+		r.Use(recoverMiddleware)
+	}
+	if c.Api.Gzip {
+		r.Use(gzipMiddleware)
+	}
+}
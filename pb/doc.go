@@ -0,0 +1,9 @@
+// Package pb holds the generated protobuf/gRPC code for the TEMS agent
+// ingestion service defined in proto/tems.proto. The generated *.pb.go files
+// are checked in (CI has no protoc) — regenerate them after editing the
+// .proto with `make protogen`, which runs:
+//
+//	protoc --go_out=. --go_opt=module=github.com/GmanStak/go-ibm-tems \
+//		--go-grpc_out=. --go-grpc_opt=module=github.com/GmanStak/go-ibm-tems \
+//		proto/tems.proto
+package pb
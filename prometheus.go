@@ -0,0 +1,134 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// -------------- Prometheus 导出 --------------
+
+// metricsCollector 实现 prometheus.Collector，按需从 Store 生成样本，
+// 避免在没有抓取请求时维护一份重复的 gauge 状态。
+type metricsCollector struct {
+	cpuDesc      *prometheus.Desc
+	memDesc      *prometheus.Desc
+	diskDesc     *prometheus.Desc
+	lastSeenDesc *prometheus.Desc
+	netDesc      *prometheus.Desc
+	procCPUDesc  *prometheus.Desc
+
+	tepsSuccessDesc *prometheus.Desc
+	tepsLatencyDesc *prometheus.Desc
+	tepsQueueDesc   *prometheus.Desc
+}
+
+func newMetricsCollector() *metricsCollector {
+	return &metricsCollector{
+		cpuDesc: prometheus.NewDesc("agent_cpu_percent", "Agent CPU usage percent",
+			[]string{"hostname", "ip"}, nil),
+		memDesc: prometheus.NewDesc("agent_mem_percent", "Agent memory usage percent",
+			[]string{"hostname", "ip"}, nil),
+		diskDesc: prometheus.NewDesc("agent_disk_percent", "Agent disk usage percent",
+			[]string{"hostname", "ip"}, nil),
+		lastSeenDesc: prometheus.NewDesc("agent_last_seen", "Unix timestamp of the last metric received from the agent",
+			[]string{"hostname", "ip"}, nil),
+		netDesc: prometheus.NewDesc("agent_network_bytes", "Per-interface network counter reported by the agent",
+			[]string{"hostname", "ip", "interface", "direction"}, nil),
+		procCPUDesc: prometheus.NewDesc("agent_process_cpu_percent", "Per-process CPU usage percent reported by the agent",
+			[]string{"hostname", "ip", "pid", "name"}, nil),
+		tepsSuccessDesc: prometheus.NewDesc("teps_push_success_total", "Total number of successful pushes to TEPS",
+			nil, nil),
+		tepsLatencyDesc: prometheus.NewDesc("teps_push_latency_seconds", "Duration of the most recent push attempt to TEPS",
+			nil, nil),
+		tepsQueueDesc: prometheus.NewDesc("teps_push_queue_depth", "Number of batches currently queued in the on-disk WAL waiting to be resent to TEPS",
+			nil, nil),
+	}
+}
+
+func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.cpuDesc
+	ch <- c.memDesc
+	ch <- c.diskDesc
+	ch <- c.lastSeenDesc
+	ch <- c.netDesc
+	if cfg.Prometheus.ProcessCPU {
+		ch <- c.procCPUDesc
+	}
+	ch <- c.tepsSuccessDesc
+	ch <- c.tepsLatencyDesc
+	ch <- c.tepsQueueDesc
+}
+
+func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	list, err := store.List()
+	if err != nil {
+		return
+	}
+
+	for _, m := range list {
+		ch <- prometheus.MustNewConstMetric(c.cpuDesc, prometheus.GaugeValue, m.CPU, m.Hostname, m.IP)
+		ch <- prometheus.MustNewConstMetric(c.memDesc, prometheus.GaugeValue, m.Mem, m.Hostname, m.IP)
+		ch <- prometheus.MustNewConstMetric(c.diskDesc, prometheus.GaugeValue, m.Disk, m.Hostname, m.IP)
+		ch <- prometheus.MustNewConstMetric(c.lastSeenDesc, prometheus.GaugeValue, float64(m.LastSeen), m.Hostname, m.IP)
+		c.collectNetwork(ch, m)
+		if cfg.Prometheus.ProcessCPU {
+			c.collectProcesses(ch, m)
+		}
+	}
+
+	successTotal, lastLatency, queueDepth := snapshotTEPSStats()
+	ch <- prometheus.MustNewConstMetric(c.tepsSuccessDesc, prometheus.CounterValue, float64(successTotal))
+	ch <- prometheus.MustNewConstMetric(c.tepsLatencyDesc, prometheus.GaugeValue, lastLatency)
+	ch <- prometheus.MustNewConstMetric(c.tepsQueueDesc, prometheus.GaugeValue, float64(queueDepth))
+}
+
+func (c *metricsCollector) collectNetwork(ch chan<- prometheus.Metric, m Metric) {
+	for iface, v := range m.Network {
+		counters, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, dir := range []string{"rx_bytes", "tx_bytes"} {
+			val, ok := counters[dir].(float64)
+			if !ok {
+				continue
+			}
+			direction := "rx"
+			if dir == "tx_bytes" {
+				direction = "tx"
+			}
+			ch <- prometheus.MustNewConstMetric(c.netDesc, prometheus.CounterValue, val, m.Hostname, m.IP, iface, direction)
+		}
+	}
+}
+
+// collectProcesses 按 pid/name 暴露每个进程的 CPU 占用，仅在配置开启时调用，
+// 避免进程数较多的机器产生过高的标签基数。
+func (c *metricsCollector) collectProcesses(ch chan<- prometheus.Metric, m Metric) {
+	for _, p := range m.Processes {
+		proc, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := proc["name"].(string)
+		pidF, _ := proc["pid"].(float64)
+		cpuF, _ := proc["cpu_percent"].(float64)
+		ch <- prometheus.MustNewConstMetric(c.procCPUDesc, prometheus.GaugeValue, cpuF,
+			m.Hostname, m.IP, strconv.FormatInt(int64(pidF), 10), name)
+	}
+}
+
+// promHandler 返回挂载在 /metrics/prom 上的 http.Handler，使用独立的
+// Registry 而不是默认的全局 Registry，避免拉入 Go runtime 自带的指标。
+func promHandler() http.Handler {
+	reg := prometheus.NewRegistry()
+	var collector prometheus.Collector = newMetricsCollector()
+	if len(cfg.Prometheus.Relabel) > 0 {
+		collector = prometheus.WrapCollectorWith(prometheus.Labels(cfg.Prometheus.Relabel), collector)
+	}
+	reg.MustRegister(collector)
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// -------------- HTTPS / mTLS / HTTP2 --------------
+
+// TLSConfig 描述 HTTPS 监听需要的证书、可选双向 TLS 以及协议细节。
+// 开启 ClientCAFile 后，TLS 握手层面只是"允许"客户端带证书（并校验
+// 证书链合法性），真正"要求"证书、校验 AllowedCNs 白名单是
+// requireClientCert 中间件的事，而且只挂在 /metrics 上——这样 mTLS
+// 只替换 /metrics 的 Basic Auth，不会连累同一个 HTTPS 监听口上的
+// /api、/web、/ws 这些浏览器路由。
+type TLSConfig struct {
+	CertFile     string   `yaml:"cert_file"`
+	KeyFile      string   `yaml:"key_file"`
+	ClientCAFile string   `yaml:"client_ca_file"`
+	AllowedCNs   []string `yaml:"allowed_cns"`
+	MinVersion   string   `yaml:"min_version"` // "1.2"(默认) 或 "1.3"
+	CipherSuites []string `yaml:"cipher_suites"`
+}
+
+func parseTLSVersion(v string) uint16 {
+	if v == "1.3" {
+		return tls.VersionTLS13
+	}
+	return tls.VersionTLS12
+}
+
+func parseCipherSuites(names []string) ([]uint16, error) {
+	byName := make(map[string]uint16, len(tls.CipherSuites()))
+	for _, s := range tls.CipherSuites() {
+		byName[s.Name] = s.ID
+	}
+	ids := make([]uint16, 0, len(names))
+	for _, n := range names {
+		id, ok := byName[n]
+		if !ok {
+			return nil, fmt.Errorf("tls: unknown cipher suite %q", n)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func buildTLSConfig(c TLSConfig) (*tls.Config, error) {
+	tlsCfg := &tls.Config{MinVersion: parseTLSVersion(c.MinVersion)}
+	if len(c.CipherSuites) > 0 {
+		suites, err := parseCipherSuites(c.CipherSuites)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.CipherSuites = suites
+	}
+	if c.ClientCAFile == "" {
+		return tlsCfg, nil
+	}
+
+	pem, err := os.ReadFile(c.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("tls: read client ca: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("tls: client ca file contains no usable certificates")
+	}
+	tlsCfg.ClientCAs = pool
+	// 用 VerifyClientCertIfGiven 而不是 RequireAndVerifyClientCert：握手阶段
+	// 只在客户端真的带了证书时才校验证书链，不带证书也能完成握手——是否
+	// "必须"带证书留给 requireClientCert 中间件按路由判断。
+	tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	return tlsCfg, nil
+}
+
+// requireClientCert 是只挂在 /metrics 上的中间件：mTLS 开启
+// （tls.client_ca_file 非空）时，要求这个请求带有效的客户端证书，
+// allowedCNs 非空时还要求证书 CN 在白名单里。其余路由不经过这个
+// 中间件，所以不会被迫要求证书。
+func requireClientCert(allowedCNs []string, next http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(allowedCNs))
+	for _, cn := range allowedCNs {
+		allowed[cn] = true
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			http.Error(w, "client certificate required", http.StatusUnauthorized)
+			return
+		}
+		if len(allowed) > 0 && !allowed[r.TLS.PeerCertificates[0].Subject.CommonName] {
+			http.Error(w, "client certificate not authorized", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+const (
+	serverReadHeaderTimeout = 10 * time.Second
+	serverIdleTimeout       = 120 * time.Second
+	shutdownTimeout         = 10 * time.Second
+)
+
+// runServers 启动明文（可选 H2C）和 HTTPS（可选 mTLS + HTTP/2）两个监听口，
+// 并在收到 SIGINT/SIGTERM 时优雅关闭，取代原来裸的 http.ListenAndServe。
+// c.ListenAddr 留空表示不开明文监听口——mTLS 模式下 /metrics 想完全
+// 取代 Basic Auth，就必须能关掉明文 /metrics 入口，否则它一直零鉴权
+// 可达。
+func runServers(handler http.Handler, c Config) {
+	var servers []*http.Server
+
+	if c.ListenAddr != "" {
+		plainHandler := handler
+		if c.H2C {
+			plainHandler = h2c.NewHandler(handler, &http2.Server{})
+		}
+		plain := &http.Server{
+			Addr:              c.ListenAddr,
+			Handler:           plainHandler,
+			ReadHeaderTimeout: serverReadHeaderTimeout,
+			IdleTimeout:       serverIdleTimeout,
+		}
+		servers = append(servers, plain)
+		go func() {
+			log.Printf("http listening on %s", c.ListenAddr)
+			if err := plain.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("http server: %v", err)
+			}
+		}()
+	}
+
+	if c.ListenAddrTLS != "" && c.TLS.CertFile != "" {
+		tlsCfg, err := buildTLSConfig(c.TLS)
+		if err != nil {
+			log.Fatalf("tls config: %v", err)
+		}
+		tlsSrv := &http.Server{
+			Addr:              c.ListenAddrTLS,
+			Handler:           handler,
+			TLSConfig:         tlsCfg,
+			ReadHeaderTimeout: serverReadHeaderTimeout,
+			IdleTimeout:       serverIdleTimeout,
+		}
+		if err := http2.ConfigureServer(tlsSrv, &http2.Server{}); err != nil {
+			log.Fatalf("configure http2: %v", err)
+		}
+		servers = append(servers, tlsSrv)
+		go func() {
+			log.Printf("https listening on %s", c.ListenAddrTLS)
+			if err := tlsSrv.ListenAndServeTLS(c.TLS.CertFile, c.TLS.KeyFile); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("https server: %v", err)
+			}
+		}()
+	}
+
+	waitForShutdown(servers)
+}
+
+func waitForShutdown(servers []*http.Server) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	<-sig
+	log.Printf("shutdown signal received, draining connections")
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	for _, s := range servers {
+		if err := s.Shutdown(ctx); err != nil {
+			log.Printf("shutdown %s: %v", s.Addr, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import "errors"
+
+// -------------- 存储抽象 --------------
+
+// ErrNotFound 表示 Store 中不存在对应 hostname 的记录。
+var ErrNotFound = errors.New("store: not found")
+
+// Store 把 TEMS 收到的 Agent 指标持久化起来。内存实现是默认值，保持和旧版本
+// 行为一致；Bolt/SQL 实现让数据在 TEMS 重启后依然可用，并支持按时间做增量查询。
+type Store interface {
+	// Put 写入或覆盖一条 hostname 对应的最新指标。
+	Put(m Metric) error
+	// Get 读取单个 hostname 的最新指标，不存在时返回 ErrNotFound。
+	Get(hostname string) (Metric, error)
+	// List 返回当前全部 hostname 的最新指标快照。
+	List() ([]Metric, error)
+	// Since 返回 LastSeen >= ts 的指标，供增量推送使用。
+	Since(ts int64) ([]Metric, error)
+	// Delete 移除一个 hostname 的记录。
+	Delete(hostname string) error
+}
+
+// newStore 根据 config.yaml 里的 storage.backend 选择具体实现。
+func newStore(c Config) (Store, error) {
+	switch c.Storage.Backend {
+	case "bolt":
+		return newBoltStore(c.Storage.Bolt.Path)
+	case "sql":
+		return newSQLStore(c.Storage.SQL)
+	case "", "memory":
+		return newMemoryStore(), nil
+	default:
+		return nil, errors.New("store: unknown backend " + c.Storage.Backend)
+	}
+}
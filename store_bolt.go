@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var metricsBucket = []byte("metrics")
+
+// boltStore 把每个 hostname 的最新指标序列化为 JSON，存进一个本地 BoltDB 文件，
+// 适合单机部署但又希望重启后保留数据的场景，不需要额外起一个数据库进程。
+type boltStore struct {
+	db *bolt.DB
+}
+
+func newBoltStore(path string) (*boltStore, error) {
+	if path == "" {
+		path = "tems.db"
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(metricsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltStore{db: db}, nil
+}
+
+func (s *boltStore) Put(m Metric) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(metricsBucket).Put([]byte(m.Hostname), b)
+	})
+}
+
+func (s *boltStore) Get(hostname string) (Metric, error) {
+	var m Metric
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(metricsBucket).Get([]byte(hostname))
+		if b == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(b, &m)
+	})
+	return m, err
+}
+
+func (s *boltStore) List() ([]Metric, error) {
+	var list []Metric
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(metricsBucket).ForEach(func(_, v []byte) error {
+			var m Metric
+			if err := json.Unmarshal(v, &m); err != nil {
+				return err
+			}
+			list = append(list, m)
+			return nil
+		})
+	})
+	return list, err
+}
+
+func (s *boltStore) Since(ts int64) ([]Metric, error) {
+	all, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	var list []Metric
+	for _, m := range all {
+		if m.LastSeen >= ts {
+			list = append(list, m)
+		}
+	}
+	return list, nil
+}
+
+func (s *boltStore) Delete(hostname string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(metricsBucket).Delete([]byte(hostname))
+	})
+}
+
+// Close releases the BoltDB file lock; used by `tems migrate`.
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
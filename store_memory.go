@@ -0,0 +1,60 @@
+package main
+
+import "sync"
+
+// memoryStore 是默认的存储实现，行为和重构前的 metrics map 一致：进程重启后
+// 数据丢失，仅适合单实例、不要求历史查询的部署。
+type memoryStore struct {
+	mu   sync.RWMutex
+	data map[string]Metric
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{data: make(map[string]Metric)}
+}
+
+func (s *memoryStore) Put(m Metric) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[m.Hostname] = m
+	return nil
+}
+
+func (s *memoryStore) Get(hostname string) (Metric, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m, ok := s.data[hostname]
+	if !ok {
+		return Metric{}, ErrNotFound
+	}
+	return m, nil
+}
+
+func (s *memoryStore) List() ([]Metric, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]Metric, 0, len(s.data))
+	for _, m := range s.data {
+		list = append(list, m)
+	}
+	return list, nil
+}
+
+func (s *memoryStore) Since(ts int64) ([]Metric, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var list []Metric
+	for _, m := range s.data {
+		if m.LastSeen >= ts {
+			list = append(list, m)
+		}
+	}
+	return list, nil
+}
+
+func (s *memoryStore) Delete(hostname string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, hostname)
+	return nil
+}
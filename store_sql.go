@@ -0,0 +1,155 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+)
+
+// SQLConfig 描述一个 master/slaves 布局的 SQL 存储：写走 Master，读每次
+// 随机挑一个 Slave；Slaves 为空时读也走 Master。
+type SQLConfig struct {
+	Driver string   `yaml:"driver"` // mysql 或 postgres
+	Master string   `yaml:"master"` // DSN
+	Slaves []string `yaml:"slaves"` // DSN 列表
+}
+
+// sqlStore 把指标存进一张 metrics(hostname, payload, last_seen) 表，
+// payload 是整条 Metric 的 JSON 编码，避免跟着 Metric 字段变动迁移表结构。
+type sqlStore struct {
+	driver string
+	master *sql.DB
+	slaves []*sql.DB
+}
+
+const createTableSQL = `CREATE TABLE IF NOT EXISTS metrics (
+	hostname VARCHAR(255) PRIMARY KEY,
+	payload TEXT NOT NULL,
+	last_seen BIGINT NOT NULL
+)`
+
+func newSQLStore(c SQLConfig) (*sqlStore, error) {
+	if c.Driver == "" {
+		c.Driver = "mysql"
+	}
+	master, err := sql.Open(c.Driver, c.Master)
+	if err != nil {
+		return nil, fmt.Errorf("sql store: open master: %w", err)
+	}
+	if _, err := master.Exec(createTableSQL); err != nil {
+		return nil, fmt.Errorf("sql store: migrate: %w", err)
+	}
+
+	s := &sqlStore{driver: c.Driver, master: master}
+	for _, dsn := range c.Slaves {
+		slave, err := sql.Open(c.Driver, dsn)
+		if err != nil {
+			return nil, fmt.Errorf("sql store: open slave: %w", err)
+		}
+		s.slaves = append(s.slaves, slave)
+	}
+	return s, nil
+}
+
+// reader 随机选一个 Slave 连接（不检查它是否可用）；没有配置 Slaves 时退回到 Master。
+func (s *sqlStore) reader() *sql.DB {
+	if len(s.slaves) == 0 {
+		return s.master
+	}
+	return s.slaves[rand.Intn(len(s.slaves))]
+}
+
+func (s *sqlStore) Put(m Metric) error {
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = s.master.Exec(
+		s.upsertSQL(),
+		m.Hostname, string(payload), m.LastSeen,
+	)
+	return err
+}
+
+// upsertSQL 返回按 driver 适配的 upsert 语句，mysql 和 postgres 的
+// "insert or update" 语法不同。
+func (s *sqlStore) upsertSQL() string {
+	if s.driver == "postgres" {
+		return `INSERT INTO metrics (hostname, payload, last_seen) VALUES ($1, $2, $3)
+			ON CONFLICT (hostname) DO UPDATE SET payload = EXCLUDED.payload, last_seen = EXCLUDED.last_seen`
+	}
+	return `INSERT INTO metrics (hostname, payload, last_seen) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE payload = VALUES(payload), last_seen = VALUES(last_seen)`
+}
+
+func (s *sqlStore) Get(hostname string) (Metric, error) {
+	var payload string
+	err := s.reader().QueryRow(`SELECT payload FROM metrics WHERE hostname = `+s.placeholder(1), hostname).Scan(&payload)
+	if err == sql.ErrNoRows {
+		return Metric{}, ErrNotFound
+	}
+	if err != nil {
+		return Metric{}, err
+	}
+	var m Metric
+	return m, json.Unmarshal([]byte(payload), &m)
+}
+
+func (s *sqlStore) List() ([]Metric, error) {
+	return s.query(`SELECT payload FROM metrics`)
+}
+
+func (s *sqlStore) Since(ts int64) ([]Metric, error) {
+	return s.query(`SELECT payload FROM metrics WHERE last_seen >= `+s.placeholder(1), ts)
+}
+
+// placeholder returns the n-th bind placeholder for the configured driver:
+// postgres uses $1, $2, ...; mysql/sqlite use positional "?".
+func (s *sqlStore) placeholder(n int) string {
+	if s.driver == "postgres" {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+func (s *sqlStore) query(q string, args ...interface{}) ([]Metric, error) {
+	rows, err := s.reader().Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []Metric
+	for rows.Next() {
+		var payload string
+		if err := rows.Scan(&payload); err != nil {
+			return nil, err
+		}
+		var m Metric
+		if err := json.Unmarshal([]byte(payload), &m); err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+func (s *sqlStore) Delete(hostname string) error {
+	_, err := s.master.Exec(`DELETE FROM metrics WHERE hostname = `+s.placeholder(1), hostname)
+	return err
+}
+
+// Close closes the master connection and every slave; used by `tems migrate`.
+func (s *sqlStore) Close() error {
+	err := s.master.Close()
+	for _, slave := range s.slaves {
+		if closeErr := slave.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TEPSConfig 控制向 TEPS 推送的可靠性相关行为，Interval/URL 仍然复用
+// Config 顶层的 teps_url/interval 字段，这里只放新增的部分。
+type TEPSConfig struct {
+	DeltaOnly   bool          `yaml:"delta_only"`
+	Gzip        bool          `yaml:"gzip"`
+	Timeout     time.Duration `yaml:"timeout"`
+	MaxRetries  int           `yaml:"max_retries"`
+	MaxBackoff  time.Duration `yaml:"max_backoff"`
+	WALPath     string        `yaml:"wal_path"`
+	WALMaxBytes int64         `yaml:"wal_max_bytes"`
+}
+
+// tepsPublisher 取代了原来在 ticker 循环里直接 http.Post 的做法：带超时的
+// client、失败退避重试、落盘 WAL 保证失败的批次不会在重启后丢失，以及可选
+// 的增量模式和 gzip 压缩。
+type tepsPublisher struct {
+	cfg      Config
+	client   *http.Client
+	wal      *walQueue
+	lastPush int64
+}
+
+func newTEPSPublisher(c Config) *tepsPublisher {
+	timeout := c.Teps.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &tepsPublisher{
+		cfg:    c,
+		client: &http.Client{Timeout: timeout},
+		wal:    newWALQueue(c.Teps.WALPath, c.Teps.WALMaxBytes),
+	}
+}
+
+// Run 是发布者的主循环，直到 ctx 被取消。每个 tick 先重放 WAL 里积压的
+// 批次，再推送这一轮的新数据。
+func (p *tepsPublisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	p.drainWAL(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.drainWAL(ctx)
+			p.pushOnce(ctx)
+		}
+	}
+}
+
+func (p *tepsPublisher) pushOnce(ctx context.Context) {
+	var since int64
+	if p.cfg.Teps.DeltaOnly {
+		since = p.lastPush
+	}
+	list, err := store.Since(since)
+	if err != nil {
+		log.Printf("teps: read store: %v", err)
+		return
+	}
+	now := time.Now().Unix()
+
+	payload := map[string]interface{}{
+		"tems_name": p.cfg.TEMSName,
+		"timestamp": now,
+		"agents":    list,
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("teps: marshal payload: %v", err)
+		return
+	}
+
+	if err := p.send(ctx, b); err != nil {
+		log.Printf("teps: push failed after retries, queuing to WAL: %v", err)
+		if err := p.wal.Enqueue(b); err != nil {
+			log.Printf("teps: wal enqueue: %v", err)
+		}
+		recordTEPSQueueDepth(p.wal.Len())
+		return
+	}
+	p.lastPush = now
+}
+
+// drainWAL retries anything left over from a previous failed push (or a
+// previous process that crashed before it could retry) before sending the
+// current tick's batch, so the on-disk queue only grows when TEPS is
+// actually unreachable.
+func (p *tepsPublisher) drainWAL(ctx context.Context) {
+	pending, err := p.wal.Pending()
+	if err != nil {
+		log.Printf("teps: read wal: %v", err)
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	var remaining [][]byte
+	for _, item := range pending {
+		if err := p.send(ctx, item); err != nil {
+			remaining = append(remaining, item)
+			continue
+		}
+	}
+	if len(remaining) != len(pending) {
+		if err := p.wal.Replace(remaining); err != nil {
+			log.Printf("teps: wal replace: %v", err)
+		}
+	}
+	recordTEPSQueueDepth(len(remaining))
+}
+
+// send gzip-compresses payload if configured, then does exponential
+// backoff-with-jitter retries against TEPS. It returns nil as soon as one
+// attempt gets a 2xx back.
+func (p *tepsPublisher) send(ctx context.Context, payload []byte) error {
+	body, encoding, err := p.encode(payload)
+	if err != nil {
+		return err
+	}
+
+	maxRetries := p.cfg.Teps.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 5
+	}
+	backoff := 500 * time.Millisecond
+
+	var lastErr error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		start := time.Now()
+		err := p.doRequest(ctx, body, encoding)
+		recordTEPSLatency(time.Since(start).Seconds())
+		if err == nil {
+			recordTEPSSuccess()
+			return nil
+		}
+		lastErr = err
+		if attempt == maxRetries-1 {
+			break
+		}
+
+		jitter := time.Duration(rand.Int63n(int64(backoff) + 1))
+		wait := backoff + jitter
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff *= 2
+		if max := p.cfg.Teps.MaxBackoff; max > 0 && backoff > max {
+			backoff = max
+		}
+	}
+	return lastErr
+}
+
+func (p *tepsPublisher) encode(payload []byte) (body []byte, contentEncoding string, err error) {
+	if !p.cfg.Teps.Gzip {
+		return payload, "", nil
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(payload); err != nil {
+		return nil, "", err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "gzip", nil
+}
+
+func (p *tepsPublisher) doRequest(ctx context.Context, body []byte, contentEncoding string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.TEPSURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("teps: non-2xx response: %s", resp.Status)
+	}
+	return nil
+}
+
+// -------------- teps_push_* 指标 --------------
+
+var tepsStats struct {
+	mu                 sync.Mutex
+	successTotal       uint64
+	lastLatencySeconds float64
+	queueDepth         int
+}
+
+func recordTEPSSuccess() {
+	tepsStats.mu.Lock()
+	tepsStats.successTotal++
+	tepsStats.mu.Unlock()
+}
+
+func recordTEPSLatency(seconds float64) {
+	tepsStats.mu.Lock()
+	tepsStats.lastLatencySeconds = seconds
+	tepsStats.mu.Unlock()
+}
+
+func recordTEPSQueueDepth(depth int) {
+	tepsStats.mu.Lock()
+	tepsStats.queueDepth = depth
+	tepsStats.mu.Unlock()
+}
+
+func snapshotTEPSStats() (successTotal uint64, lastLatencySeconds float64, queueDepth int) {
+	tepsStats.mu.Lock()
+	defer tepsStats.mu.Unlock()
+	return tepsStats.successTotal, tepsStats.lastLatencySeconds, tepsStats.queueDepth
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+	"sync"
+)
+
+// walQueue is a bounded ring file: each line is a base64-encoded payload
+// that failed to reach TEPS. When appending would push the file past
+// maxBytes, the oldest lines are dropped first, so a TEPS outage can't
+// grow the queue file without bound.
+type walQueue struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+}
+
+func newWALQueue(path string, maxBytes int64) *walQueue {
+	if path == "" {
+		path = "teps_wal.log"
+	}
+	if maxBytes <= 0 {
+		maxBytes = 8 << 20 // 8MiB
+	}
+	return &walQueue{path: path, maxBytes: maxBytes}
+}
+
+func (q *walQueue) Enqueue(payload []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	lines, err := q.readLinesLocked()
+	if err != nil {
+		return err
+	}
+	lines = append(lines, base64.StdEncoding.EncodeToString(payload))
+	for q.sizeLocked(lines) > q.maxBytes && len(lines) > 1 {
+		lines = lines[1:]
+	}
+	return q.writeLinesLocked(lines)
+}
+
+func (q *walQueue) Pending() ([][]byte, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	lines, err := q.readLinesLocked()
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]byte, 0, len(lines))
+	for _, l := range lines {
+		b, err := base64.StdEncoding.DecodeString(l)
+		if err != nil {
+			continue // 损坏的一行，跳过而不是让整个 WAL 没法用
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+// Replace overwrites the queue with exactly these payloads, used after a
+// drain to drop everything that was successfully resent.
+func (q *walQueue) Replace(pending [][]byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	lines := make([]string, len(pending))
+	for i, b := range pending {
+		lines[i] = base64.StdEncoding.EncodeToString(b)
+	}
+	return q.writeLinesLocked(lines)
+}
+
+func (q *walQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	lines, err := q.readLinesLocked()
+	if err != nil {
+		return 0
+	}
+	return len(lines)
+}
+
+func (q *walQueue) readLinesLocked() ([]string, error) {
+	b, err := os.ReadFile(q.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	trimmed := strings.TrimRight(string(b), "\n")
+	if trimmed == "" {
+		return nil, nil
+	}
+	return strings.Split(trimmed, "\n"), nil
+}
+
+func (q *walQueue) writeLinesLocked(lines []string) error {
+	content := ""
+	if len(lines) > 0 {
+		content = strings.Join(lines, "\n") + "\n"
+	}
+	return os.WriteFile(q.path, []byte(content), 0o600)
+}
+
+func (q *walQueue) sizeLocked(lines []string) int64 {
+	var n int64
+	for _, l := range lines {
+		n += int64(len(l)) + 1
+	}
+	return n
+}
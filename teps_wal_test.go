@@ -0,0 +1,52 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestWALQueueDropsOldestWhenOverMaxBytes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal.log")
+	// "payload-N" is 9 bytes, so each base64 line is 12 bytes plus the
+	// trailing newline (13 in all); a 60-byte cap holds the four most
+	// recent entries, so enqueuing five drops the oldest.
+	q := newWALQueue(path, 60)
+
+	for i := 0; i < 5; i++ {
+		if err := q.Enqueue([]byte("payload-" + string(rune('0'+i)))); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	pending, err := q.Pending()
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) == 0 || len(pending) >= 5 {
+		t.Fatalf("expected the queue to have dropped some of the oldest entries, got %d", len(pending))
+	}
+
+	// The newest entry must have survived, i.e. the oldest entries are the
+	// ones dropped, not the newest.
+	want := "payload-" + string(rune('0'+4))
+	if got := string(pending[len(pending)-1]); got != want {
+		t.Fatalf("last pending entry = %q, want %q", got, want)
+	}
+}
+
+func TestWALQueueNeverDropsTheLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal.log")
+	q := newWALQueue(path, 1) // far smaller than any single encoded line
+
+	if err := q.Enqueue([]byte("a-single-payload-much-bigger-than-maxBytes")); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	pending, err := q.Pending()
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("expected the single oversized entry to be kept, got %d entries", len(pending))
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+
+	"golang.org/x/time/rate"
+)
+
+// -------------- Ingestion 校验 / 限流（HTTP 和 gRPC 共用）--------------
+
+var ingestLimiter *rate.Limiter
+
+// initIngestLimiter 按 grpc.rate_limit 配置一个全局限流器；两条 ingestion
+// 路径（/metrics 和 gRPC PushMetrics/Control）共用同一个 limiter，因为限流
+// 本质是保护同一个 Store，跟数据从哪条通道进来无关。未配置时不限流。
+func initIngestLimiter(c Config) {
+	if c.GRPC.RateLimit.PerSecond <= 0 {
+		ingestLimiter = nil
+		return
+	}
+	burst := c.GRPC.RateLimit.Burst
+	if burst <= 0 {
+		burst = int(c.GRPC.RateLimit.PerSecond)
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	ingestLimiter = rate.NewLimiter(rate.Limit(c.GRPC.RateLimit.PerSecond), burst)
+}
+
+// validateMetric 校验 HTTP 和 gRPC 两条 ingestion 路径共用：hostname 必填，
+// 其它字段保持宽松——agent 版本演进比较快，没必要在这里卡太死。
+func validateMetric(m Metric) error {
+	if m.Hostname == "" {
+		return errors.New("metric: hostname is required")
+	}
+	return nil
+}
+
+// allowIngest 在共享限流器上占一个名额；没配置限流时总是放行。
+func allowIngest() bool {
+	if ingestLimiter == nil {
+		return true
+	}
+	return ingestLimiter.Allow()
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// -------------- WebSocket 实时推送 --------------
+
+// wsClient 代表一个已连接的浏览器，send 是它的发送缓冲区；缓冲区满时说明
+// 这个客户端消费太慢，hub 直接把它断开而不是阻塞等待。
+type wsClient struct {
+	conn *websocket.Conn
+	send chan []byte
+}
+
+// wsHub 是一个简单的 broker：metricsHandler 把新数据发到 broadcast，
+// run() 里的 goroutine 再把它扇出给所有订阅的客户端。
+type wsHub struct {
+	mu        sync.Mutex
+	clients   map[*wsClient]struct{}
+	broadcast chan []byte
+}
+
+func newWSHub() *wsHub {
+	h := &wsHub{
+		clients:   make(map[*wsClient]struct{}),
+		broadcast: make(chan []byte, 256),
+	}
+	go h.run()
+	return h
+}
+
+func (h *wsHub) run() {
+	for msg := range h.broadcast {
+		h.mu.Lock()
+		for c := range h.clients {
+			select {
+			case c.send <- msg:
+			default: // 慢消费者：直接断开，不让它拖慢整个 hub
+				delete(h.clients, c)
+				close(c.send)
+			}
+		}
+		h.mu.Unlock()
+	}
+}
+
+func (h *wsHub) publish(m Metric) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+	select {
+	case h.broadcast <- b:
+	default: // hub 本身积压太多，丢弃这次更新
+	}
+}
+
+func (h *wsHub) addClient(conn *websocket.Conn) *wsClient {
+	c := &wsClient{conn: conn, send: make(chan []byte, 16)}
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+	go c.writePump()
+	return c
+}
+
+func (h *wsHub) removeClient(c *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.clients[c]; ok {
+		delete(h.clients, c)
+		close(c.send)
+	}
+}
+
+func (c *wsClient) writePump() {
+	defer c.conn.Close()
+	for msg := range c.send {
+		if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+			return
+		}
+	}
+}
+
+var hub = newWSHub()
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     checkWSOrigin,
+}
+
+// checkWSOrigin 校验 WebSocket 握手的 Origin。浏览器对 WS 握手不走
+// fetch/XHR 的同源策略，但会带上已缓存的 Basic-Auth 凭证，所以不能像
+// gorilla/websocket 默认实现那样一律放行——否则任意第三方页面都能代替
+// 登录过 dashboard 的浏览器打开 /ws 读取指标流。没有 Origin 头（非浏览器
+// 客户端）视为同源放行；否则要求同源，或匹配 cfg.Api.Cors.AllowedOrigins。
+func checkWSOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if u.Host == r.Host {
+		return true
+	}
+	for _, allowed := range cfg.Api.Cors.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// wsHandler 升级连接后只管读（丢弃客户端消息，仅用来检测断线），真正的数据
+// 都是 hub 单向推送给 writePump。
+func wsHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("ws upgrade: %v", err)
+		return
+	}
+	client := hub.addClient(conn)
+	defer hub.removeClient(client)
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}